@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// indexToPassword decodes idx (in [0, total)) into the password that
+// generatePasswords would have produced at that position, via mixed-radix
+// decoding over the per-position charsets. It is a pure function so that
+// shards and resumed runs are deterministic and never overlap: the same
+// (charsets, idx) pair always yields the same password.
+func indexToPassword(charsets []string, idx uint64) string {
+	out := make([]byte, len(charsets))
+	for i := len(charsets) - 1; i >= 0; i-- {
+		base := uint64(len(charsets[i]))
+		out[i] = charsets[i][idx%base]
+		idx /= base
+	}
+	return string(out)
+}
+
+// generateRangeBatched feeds ch with consecutive batches of up to
+// batchSize passwords for indices [start, end), closing ch when done.
+// It runs arbitrarily far ahead of what batchWorker has actually hashed
+// (bounded only by ch's buffering), so its progress must never be used
+// to decide what's safe to checkpoint — see crackRange's use of
+// triedCounter for that. Batching lets the executor amortize per-launch
+// overhead (GPU kernel dispatch in particular) across many passwords
+// instead of paying it one at a time.
+func generateRangeBatched(charsets []string, start, end uint64, batchSize int, ch chan<- []string) {
+	defer close(ch)
+	batch := make([]string, 0, batchSize)
+	for idx := start; idx < end; idx++ {
+		batch = append(batch, indexToPassword(charsets, idx))
+		if len(batch) == batchSize {
+			ch <- batch
+			batch = make([]string, 0, batchSize)
+		}
+	}
+	if len(batch) > 0 {
+		ch <- batch
+	}
+}
+
+// Checkpoint is the JSON state periodically written during a bruteforce
+// run so a killed run can resume with -resume instead of starting over.
+type Checkpoint struct {
+	Mask  string `json:"mask"`
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+	Next  uint64 `json:"next_index"`
+	Tried uint64 `json:"tried"`
+}
+
+func saveCheckpoint(path string, cp Checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func loadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("invalid checkpoint file: %v", err)
+	}
+	return cp, nil
+}
+
+// parseShard parses a "--shard i/N" flag value into its zero-based index
+// and shard count.
+func parseShard(s string) (index, count int, err error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid shard %q, expected i/N", s)
+	}
+	index, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard index %q: %v", parts[0], err)
+	}
+	count, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard count %q: %v", parts[1], err)
+	}
+	if count <= 0 || index < 0 || index >= count {
+		return 0, 0, fmt.Errorf("shard index %d out of range for %d shards", index, count)
+	}
+	return index, count, nil
+}
+
+// shardRange returns the contiguous, non-overlapping sub-range of
+// [0, total) that shard `index` out of `count` shards is responsible
+// for. The remainder of total/count is spread one index per shard so
+// every index in [0, total) is claimed by exactly one shard.
+func shardRange(total uint64, index, count int) (start, end uint64) {
+	chunk := total / uint64(count)
+	rem := total % uint64(count)
+
+	start = uint64(index) * chunk
+	if uint64(index) < rem {
+		start += uint64(index)
+	} else {
+		start += rem
+	}
+
+	end = start + chunk
+	if uint64(index) < rem {
+		end++
+	}
+	return start, end
+}
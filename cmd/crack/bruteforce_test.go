@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexToPassword(t *testing.T) {
+	charsets := []string{"ab", "ab", "ab"}
+	cases := []struct {
+		idx  uint64
+		want string
+	}{
+		{0, "aaa"},
+		{1, "aab"},
+		{2, "aba"},
+		{3, "abb"},
+		{4, "baa"},
+		{7, "bbb"},
+	}
+	for _, c := range cases {
+		if got := indexToPassword(charsets, c.idx); got != c.want {
+			t.Errorf("indexToPassword(%d) = %q, want %q", c.idx, got, c.want)
+		}
+	}
+}
+
+func TestIndexToPasswordMixedRadix(t *testing.T) {
+	charsets := []string{"xy", "abc"}
+	want := []string{"xa", "xb", "xc", "ya", "yb", "yc"}
+	for idx, w := range want {
+		if got := indexToPassword(charsets, uint64(idx)); got != w {
+			t.Errorf("indexToPassword(%d) = %q, want %q", idx, got, w)
+		}
+	}
+}
+
+func TestParseShard(t *testing.T) {
+	index, count, err := parseShard("2/8")
+	if err != nil {
+		t.Fatalf("parseShard: %v", err)
+	}
+	if index != 2 || count != 8 {
+		t.Errorf("parseShard(2/8) = %d, %d, want 2, 8", index, count)
+	}
+}
+
+func TestParseShardErrors(t *testing.T) {
+	cases := []string{"2", "2/8/9", "x/8", "2/x", "8/8", "-1/8", "2/0"}
+	for _, c := range cases {
+		if _, _, err := parseShard(c); err == nil {
+			t.Errorf("parseShard(%q): want error, got nil", c)
+		}
+	}
+}
+
+func TestShardRangeCoversWholeSpaceWithoutOverlap(t *testing.T) {
+	const total = uint64(103)
+	const count = 8
+
+	var prevEnd uint64
+	for index := 0; index < count; index++ {
+		start, end := shardRange(total, index, count)
+		if start != prevEnd {
+			t.Fatalf("shard %d: start = %d, want %d (contiguous with previous end)", index, start, prevEnd)
+		}
+		if end < start {
+			t.Fatalf("shard %d: end %d < start %d", index, end, start)
+		}
+		prevEnd = end
+	}
+	if prevEnd != total {
+		t.Errorf("last shard end = %d, want %d", prevEnd, total)
+	}
+}
+
+func TestShardRangeEvenSplit(t *testing.T) {
+	start, end := shardRange(100, 1, 4)
+	if start != 25 || end != 50 {
+		t.Errorf("shardRange(100, 1, 4) = %d, %d, want 25, 50", start, end)
+	}
+}
+
+func TestSaveLoadCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	want := Checkpoint{Mask: "?l?l?l", Start: 10, End: 1000, Next: 42, Tried: 32}
+
+	if err := saveCheckpoint(path, want); err != nil {
+		t.Fatalf("saveCheckpoint: %v", err)
+	}
+
+	got, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("loadCheckpoint: %v", err)
+	}
+	if got != want {
+		t.Errorf("loadCheckpoint = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadCheckpointMissingFile(t *testing.T) {
+	if _, err := loadCheckpoint(filepath.Join(t.TempDir(), "does-not-exist.json")); err == nil {
+		t.Fatal("loadCheckpoint on missing file: want error, got nil")
+	}
+}
+
+func TestLoadCheckpointInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := loadCheckpoint(path); err == nil {
+		t.Fatal("loadCheckpoint on invalid JSON: want error, got nil")
+	}
+}
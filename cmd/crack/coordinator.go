@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// errStopped is returned by claimChunk once the coordinator reports that
+// another worker already found the password, so runDistributedClient
+// stops claiming chunks instead of grinding through the rest of the
+// keyspace.
+var errStopped = errors.New("coordinator: password already found by another worker")
+
+// coordinator hands out contiguous chunks of a [0, total) keyspace to
+// worker processes over a small REST API, enabling multi-host cracking:
+//
+//	GET  /claim?size=N  -> {"start":..,"end":..} or 204 if exhausted
+//	POST /found         -> {"password":".."}
+//	POST /heartbeat     -> {"worker":".."}
+type coordinator struct {
+	mu    sync.Mutex
+	total uint64
+	next  uint64
+	found string
+}
+
+type claimResponse struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// claimStatus reports the outcome of a claim attempt: ok means chunk is
+// populated, exhausted means the keyspace has been fully handed out, and
+// stopped means another worker already reported the password found.
+type claimStatus int
+
+const (
+	claimOK claimStatus = iota
+	claimExhausted
+	claimStopped
+)
+
+func (c *coordinator) claim(size uint64) (claimResponse, claimStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.found != "" {
+		return claimResponse{}, claimStopped
+	}
+	if c.next >= c.total {
+		return claimResponse{}, claimExhausted
+	}
+	start := c.next
+	end := start + size
+	if end > c.total {
+		end = c.total
+	}
+	c.next = end
+	return claimResponse{Start: start, End: end}, claimOK
+}
+
+func (c *coordinator) handleClaim(w http.ResponseWriter, r *http.Request) {
+	size, err := strconv.ParseUint(r.URL.Query().Get("size"), 10, 64)
+	if err != nil || size == 0 {
+		http.Error(w, "invalid size parameter", http.StatusBadRequest)
+		return
+	}
+
+	chunk, status := c.claim(size)
+	switch status {
+	case claimStopped:
+		w.WriteHeader(http.StatusGone)
+		return
+	case claimExhausted:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chunk)
+}
+
+func (c *coordinator) handleFound(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.found = body.Password
+	c.mu.Unlock()
+
+	log.Printf("coordinator: password found: %s", body.Password)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *coordinator) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Worker string `json:"worker"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	log.Printf("coordinator: heartbeat from %s", body.Worker)
+	w.WriteHeader(http.StatusOK)
+}
+
+// runCoordinator starts the HTTP coordinator and blocks until it exits.
+func runCoordinator(addr string, total uint64) error {
+	c := &coordinator{total: total}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/claim", c.handleClaim)
+	mux.HandleFunc("/found", c.handleFound)
+	mux.HandleFunc("/heartbeat", c.handleHeartbeat)
+
+	log.Printf("coordinator: listening on %s, keyspace size %d", addr, total)
+	return http.ListenAndServe(addr, mux)
+}
+
+// claimChunk asks the coordinator at baseURL for a chunk of up to size
+// indices to work on. It returns errStopped once the coordinator reports
+// the password already found by another worker.
+func claimChunk(baseURL string, size uint64) (start, end uint64, ok bool, err error) {
+	resp, err := http.Get(fmt.Sprintf("%s/claim?size=%d", baseURL, size))
+	if err != nil {
+		return 0, 0, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusGone {
+		return 0, 0, false, errStopped
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return 0, 0, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, false, fmt.Errorf("coordinator returned status %d", resp.StatusCode)
+	}
+
+	var chunk claimResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chunk); err != nil {
+		return 0, 0, false, err
+	}
+	return chunk.Start, chunk.End, true, nil
+}
+
+// reportFound tells the coordinator at baseURL that password was found.
+func reportFound(baseURL, password string) error {
+	body, _ := json.Marshal(map[string]string{"password": password})
+	resp, err := http.Post(baseURL+"/found", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// sendHeartbeat tells the coordinator at baseURL that worker is alive.
+func sendHeartbeat(baseURL, worker string) error {
+	body, _ := json.Marshal(map[string]string{"worker": worker})
+	resp, err := http.Post(baseURL+"/heartbeat", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCoordinatorClaimHandsOutContiguousChunks(t *testing.T) {
+	c := &coordinator{total: 100}
+
+	chunk, status := c.claim(30)
+	if status != claimOK {
+		t.Fatalf("status = %v, want claimOK", status)
+	}
+	if chunk.Start != 0 || chunk.End != 30 {
+		t.Errorf("first claim = %+v, want {0 30}", chunk)
+	}
+
+	chunk, status = c.claim(30)
+	if status != claimOK || chunk.Start != 30 || chunk.End != 60 {
+		t.Errorf("second claim = %+v, %v, want {30 60}, claimOK", chunk, status)
+	}
+}
+
+func TestCoordinatorClaimTruncatesFinalChunk(t *testing.T) {
+	c := &coordinator{total: 100, next: 90}
+
+	chunk, status := c.claim(30)
+	if status != claimOK {
+		t.Fatalf("status = %v, want claimOK", status)
+	}
+	if chunk.Start != 90 || chunk.End != 100 {
+		t.Errorf("claim = %+v, want {90 100}", chunk)
+	}
+}
+
+func TestCoordinatorClaimExhausted(t *testing.T) {
+	c := &coordinator{total: 100, next: 100}
+
+	if _, status := c.claim(30); status != claimExhausted {
+		t.Errorf("status = %v, want claimExhausted", status)
+	}
+}
+
+func TestCoordinatorClaimStoppedOnceFound(t *testing.T) {
+	c := &coordinator{total: 100, found: "hunter2"}
+
+	if _, status := c.claim(30); status != claimStopped {
+		t.Errorf("status = %v, want claimStopped", status)
+	}
+}
+
+func TestHandleClaimStatusCodes(t *testing.T) {
+	cases := []struct {
+		name   string
+		c      *coordinator
+		query  string
+		status int
+	}{
+		{"ok", &coordinator{total: 100}, "size=10", http.StatusOK},
+		{"exhausted", &coordinator{total: 100, next: 100}, "size=10", http.StatusNoContent},
+		{"stopped", &coordinator{total: 100, found: "hunter2"}, "size=10", http.StatusGone},
+		{"bad size", &coordinator{total: 100}, "size=0", http.StatusBadRequest},
+		{"missing size", &coordinator{total: 100}, "", http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/claim?"+tc.query, nil)
+			w := httptest.NewRecorder()
+			tc.c.handleClaim(w, req)
+			if w.Code != tc.status {
+				t.Errorf("status = %d, want %d", w.Code, tc.status)
+			}
+		})
+	}
+}
+
+func TestHandleFoundSetsFoundAndStopsClaims(t *testing.T) {
+	c := &coordinator{total: 100}
+
+	req := httptest.NewRequest(http.MethodPost, "/found", strings.NewReader(`{"password":"hunter2"}`))
+	w := httptest.NewRecorder()
+	c.handleFound(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if c.found != "hunter2" {
+		t.Errorf("found = %q, want hunter2", c.found)
+	}
+	if _, status := c.claim(10); status != claimStopped {
+		t.Errorf("claim after /found: status = %v, want claimStopped", status)
+	}
+}
+
+func TestHandleFoundInvalidBody(t *testing.T) {
+	c := &coordinator{total: 100}
+
+	req := httptest.NewRequest(http.MethodPost, "/found", strings.NewReader("not json"))
+	w := httptest.NewRecorder()
+	c.handleFound(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleHeartbeatAlwaysOK(t *testing.T) {
+	c := &coordinator{total: 100}
+
+	req := httptest.NewRequest(http.MethodPost, "/heartbeat", strings.NewReader(`{"worker":"w1"}`))
+	w := httptest.NewRecorder()
+	c.handleHeartbeat(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestClaimChunkRoundTrip(t *testing.T) {
+	c := &coordinator{total: 100}
+	srv := httptest.NewServer(http.HandlerFunc(c.handleClaim))
+	defer srv.Close()
+
+	start, end, ok, err := claimChunk(srv.URL, 30)
+	if err != nil {
+		t.Fatalf("claimChunk: %v", err)
+	}
+	if !ok || start != 0 || end != 30 {
+		t.Errorf("claimChunk = %d, %d, %v, want 0, 30, true", start, end, ok)
+	}
+}
+
+func TestClaimChunkReturnsErrStoppedOnGone(t *testing.T) {
+	c := &coordinator{total: 100, found: "hunter2"}
+	srv := httptest.NewServer(http.HandlerFunc(c.handleClaim))
+	defer srv.Close()
+
+	_, _, ok, err := claimChunk(srv.URL, 30)
+	if ok {
+		t.Fatal("claimChunk: ok = true, want false")
+	}
+	if err != errStopped {
+		t.Errorf("err = %v, want errStopped", err)
+	}
+}
+
+func TestClaimChunkReturnsNotOkOnExhausted(t *testing.T) {
+	c := &coordinator{total: 100, next: 100}
+	srv := httptest.NewServer(http.HandlerFunc(c.handleClaim))
+	defer srv.Close()
+
+	_, _, ok, err := claimChunk(srv.URL, 30)
+	if err != nil {
+		t.Fatalf("claimChunk: %v", err)
+	}
+	if ok {
+		t.Fatal("claimChunk: ok = true, want false on exhausted keyspace")
+	}
+}
+
+func TestReportFoundAndSendHeartbeat(t *testing.T) {
+	c := &coordinator{total: 100}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/found", c.handleFound)
+	mux.HandleFunc("/heartbeat", c.handleHeartbeat)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	if err := reportFound(srv.URL, "hunter2"); err != nil {
+		t.Fatalf("reportFound: %v", err)
+	}
+	if c.found != "hunter2" {
+		t.Errorf("found = %q, want hunter2", c.found)
+	}
+	if err := sendHeartbeat(srv.URL, "worker-1"); err != nil {
+		t.Fatalf("sendHeartbeat: %v", err)
+	}
+}
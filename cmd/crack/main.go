@@ -0,0 +1,516 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/LyashenkoAndron/scrypt/lab2"
+)
+
+// parseFile reads a hash file and decodes it, auto-detecting whether it
+// uses the legacy N*r*p*keyLen*salt*hash text format, a PHC/crypt7
+// string ($scrypt$... or $7$...), or the Reed-Solomon-protected binary
+// envelope (magic "SCRY"). corrected is non-zero only for the binary
+// envelope, and reports how many shares had to be error-corrected.
+func parseFile(filename string) (data *lab2.Decoded, corrected int, err error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if bytes.HasPrefix(content, []byte(lab2.RSMagic)) {
+		data, corrected, err = lab2.DecodeRSFile(content)
+		return data, corrected, err
+	}
+
+	data, err = lab2.Parse(string(content))
+	return data, 0, err
+}
+
+func getCharset(m rune) string {
+	switch m {
+	case 'a':
+		return "qwertyuiopasdfghjklzxcvbnmQWERTYUIOPASDFGHJKLZXCVBNM1234567890"
+	case 'd':
+		return "1234567890"
+	case 'l':
+		return "qwertyuiopasdfghjklzxcvbnm"
+	case 'u':
+		return "QWERTYUIOPASDFGHJKLZXCVBNM"
+	case 's':
+		return "!@#$%^&*()_+-=[]{}|;:,.<>?"
+	}
+	return ""
+}
+
+// batchWorker drains batches of candidate passwords, asks exec to hash
+// each batch under params, and reports the first password whose hash
+// matches target. Batch-level parallelism (if any) is the executor's
+// job: cpuExecutor fans a batch out across CPU cores.
+func batchWorker(ctx context.Context, batches <-chan []string, exec lab2.Executor, params lab2.Params, target []byte, found *atomic.Bool, result chan<- string, tried *atomic.Uint64) {
+	for batch := range batches {
+		if found.Load() {
+			for range batches {
+			}
+			return
+		}
+
+		hashes, err := exec.Run(ctx, batch, params)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nwarning: executor error: %v\n", err)
+			continue
+		}
+		tried.Add(uint64(len(batch)))
+
+		for i, hash := range hashes {
+			if bytes.Equal(hash, target) {
+				if found.CompareAndSwap(false, true) {
+					result <- batch[i]
+				}
+				return
+			}
+		}
+	}
+}
+
+// maskCombinations returns the number of passwords a compiled mask (as
+// returned by parseMask) can produce: the cartesian product of its
+// per-position charset sizes.
+func maskCombinations(charsets []string) uint64 {
+	total := uint64(1)
+	for _, cs := range charsets {
+		total *= uint64(len(cs))
+	}
+	return total
+}
+
+// crackRange runs exec over password indices [rangeStart, rangeEnd),
+// batching batchSize candidates per exec.Run call, and optionally
+// checkpoints progress to checkpointPath every checkpointInterval.
+// globalTotal is only used for progress reporting when
+// rangeEnd-rangeStart is a sub-range of the full keyspace.
+func crackRange(ctx context.Context, data *lab2.Decoded, exec lab2.Executor, charsets []string, mask string, rangeStart, rangeEnd, globalTotal uint64, batchSize int, checkpointPath string, checkpointInterval time.Duration) (password string, tried uint64, found bool) {
+	localTotal := rangeEnd - rangeStart
+
+	batches := make(chan []string, 4)
+	result := make(chan string, 1)
+	var foundFlag atomic.Bool
+	var triedCounter atomic.Uint64
+
+	go generateRangeBatched(charsets, rangeStart, rangeEnd, batchSize, batches)
+
+	workerDone := make(chan struct{})
+	go func() {
+		batchWorker(ctx, batches, exec, data.Params, data.Hash, &foundFlag, result, &triedCounter)
+		close(workerDone)
+	}()
+
+	progressTicker := time.NewTicker(1 * time.Second)
+	defer progressTicker.Stop()
+
+	var checkpointChan <-chan time.Time
+	if checkpointPath != "" {
+		checkpointTicker := time.NewTicker(checkpointInterval)
+		defer checkpointTicker.Stop()
+		checkpointChan = checkpointTicker.C
+	}
+
+	start := time.Now()
+
+	for {
+		select {
+		case <-progressTicker.C:
+			count := triedCounter.Load()
+			speed := float64(count) / time.Since(start).Seconds()
+			if localTotal > 0 {
+				progress := float64(count) / float64(localTotal) * 100
+				fmt.Printf("\rProgress: %.2f%% | Tried: %d/%d (global keyspace %d) | Speed: %.0f pwd/s    ",
+					progress, count, localTotal, globalTotal, speed)
+			}
+
+		case <-checkpointChan:
+			// rangeStart+triedCounter, not the generator's frontier: the
+			// generator runs batches ahead of what batchWorker has
+			// actually hashed, and checkpointing that would let -resume
+			// skip right over the real password.
+			if err := saveCheckpoint(checkpointPath, Checkpoint{
+				Mask: mask, Start: rangeStart, End: rangeEnd,
+				Next: rangeStart + triedCounter.Load(), Tried: triedCounter.Load(),
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "\nwarning: failed to write checkpoint: %v\n", err)
+			}
+
+		case pwd := <-result:
+			return pwd, triedCounter.Load(), true
+
+		case <-workerDone:
+			return "", triedCounter.Load(), false
+		}
+	}
+}
+
+// crackWithSource runs exec against an arbitrary Source, for the
+// wordlist, rule, and hybrid attack modes, batching batchSize candidates
+// per exec.Run call. Unlike crackRange it has no notion of a contiguous
+// index range, so it doesn't support -shard, -checkpoint, or -resume.
+func crackWithSource(ctx context.Context, data *lab2.Decoded, exec lab2.Executor, src Source, total uint64, batchSize int) (password string, tried uint64, found bool) {
+	batches := make(chan []string, 4)
+	result := make(chan string, 1)
+	var foundFlag atomic.Bool
+	var triedCounter atomic.Uint64
+
+	go func() {
+		defer close(batches)
+		batch := make([]string, 0, batchSize)
+		for {
+			pw, ok := src.Next()
+			if !ok {
+				break
+			}
+			batch = append(batch, pw)
+			if len(batch) == batchSize {
+				batches <- batch
+				batch = make([]string, 0, batchSize)
+			}
+		}
+		if len(batch) > 0 {
+			batches <- batch
+		}
+	}()
+
+	workerDone := make(chan struct{})
+	go func() {
+		batchWorker(ctx, batches, exec, data.Params, data.Hash, &foundFlag, result, &triedCounter)
+		close(workerDone)
+	}()
+
+	progressTicker := time.NewTicker(1 * time.Second)
+	defer progressTicker.Stop()
+
+	start := time.Now()
+
+	for {
+		select {
+		case <-progressTicker.C:
+			count := triedCounter.Load()
+			speed := float64(count) / time.Since(start).Seconds()
+			if total > 0 {
+				progress := float64(count) / float64(total) * 100
+				fmt.Printf("\rProgress: %.2f%% | Tried: %d/%d | Speed: %.0f pwd/s    ", progress, count, total, speed)
+			} else {
+				fmt.Printf("\rTried: %d | Speed: %.0f pwd/s    ", count, speed)
+			}
+
+		case pwd := <-result:
+			return pwd, triedCounter.Load(), true
+
+		case <-workerDone:
+			return "", triedCounter.Load(), false
+		}
+	}
+}
+
+// runDistributedClient repeatedly claims chunks of the keyspace from a
+// coordinator and cracks each one until a password is found or the
+// coordinator has nothing left to hand out.
+func runDistributedClient(ctx context.Context, data *lab2.Decoded, exec lab2.Executor, charsets []string, mask, coordinatorURL string, chunkSize, total uint64, batchSize int) {
+	workerID := fmt.Sprintf("worker-%d", os.Getpid())
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			sendHeartbeat(coordinatorURL, workerID)
+		}
+	}()
+
+	for {
+		start, end, ok, err := claimChunk(coordinatorURL, chunkSize)
+		if errors.Is(err, errStopped) {
+			fmt.Println("\nAnother worker already found the password; stopping.")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error claiming chunk from coordinator: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			fmt.Println("\nNo more chunks to claim; keyspace exhausted.")
+			return
+		}
+
+		fmt.Printf("Claimed chunk [%d, %d)\n", start, end)
+		pwd, tried, found := crackRange(ctx, data, exec, charsets, mask, start, end, total, batchSize, "", 0)
+		if found {
+			if err := reportFound(coordinatorURL, pwd); err != nil {
+				fmt.Fprintf(os.Stderr, "\nwarning: failed to report found password: %v\n", err)
+			}
+			fmt.Printf("\n\nPASSWORD FOUND: %s\n", pwd)
+			fmt.Printf("Attempts in this chunk: %d\n", tried)
+			return
+		}
+	}
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [-m <mask>] [-w <wordlist>] <hash_file>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\nMask syntax (-m), hashcat-style:\n")
+	fmt.Fprintf(os.Stderr, "  ?a - all characters (letters + digits)   ?d - digits\n")
+	fmt.Fprintf(os.Stderr, "  ?l - lowercase letters                   ?u - uppercase letters\n")
+	fmt.Fprintf(os.Stderr, "  ?s - special symbols                     ?? - literal '?'\n")
+	fmt.Fprintf(os.Stderr, "  ?1-?4 - custom charset from -custom1..-custom4\n")
+	fmt.Fprintf(os.Stderr, "  any other character is a literal, e.g. \"Pass?d?d\" tries Pass00..Pass99\n")
+	fmt.Fprintf(os.Stderr, "\nExample: %s -m ?l?l?l?d?d hash.txt\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\nWordlist and rule options:\n")
+	fmt.Fprintf(os.Stderr, "  -w file               : try each word in file\n")
+	fmt.Fprintf(os.Stderr, "  -rules file           : mutate -w words with John/hashcat-style rules\n")
+	fmt.Fprintf(os.Stderr, "  -hybrid mode          : combine -m and -w; mode is mask+wordlist or wordlist+mask\n")
+	fmt.Fprintf(os.Stderr, "\nDistributed/resumable options (mask mode only):\n")
+	fmt.Fprintf(os.Stderr, "  -shard i/N            : only try this host's contiguous slice of the keyspace\n")
+	fmt.Fprintf(os.Stderr, "  -checkpoint file      : periodically save progress for -resume\n")
+	fmt.Fprintf(os.Stderr, "  -resume file          : resume a run from a checkpoint file\n")
+	fmt.Fprintf(os.Stderr, "  -coordinator addr     : serve keyspace chunks to workers over HTTP\n")
+	fmt.Fprintf(os.Stderr, "  -coordinator-url url  : pull chunks from a coordinator instead of -shard\n")
+	fmt.Fprintf(os.Stderr, "\nReed-Solomon-protected hash files (magic \"SCRY\", produced by gen -rs):\n")
+	fmt.Fprintf(os.Stderr, "  -fix                  : rewrite the file with fresh shards after correcting bit-rot\n")
+	fmt.Fprintf(os.Stderr, "\nExecutor options:\n")
+	fmt.Fprintf(os.Stderr, "  -device cpu|gpu|auto  : where to run the KDF (default: cpu)\n")
+	fmt.Fprintf(os.Stderr, "  -batch N              : candidates per executor dispatch (default: 1000)\n")
+	fmt.Fprintf(os.Stderr, "  -bench                : benchmark every executor at the given N/r/p and exit\n")
+}
+
+func main() {
+	maskFlag := flag.String("m", "", "Password mask, hashcat-style (?l ?u ?d ?s ?a, ?1-?4, literals)")
+	wordlistFlag := flag.String("w", "", "Wordlist file for dictionary/hybrid attack modes")
+	rulesFlag := flag.String("rules", "", "Rule file to mutate -w candidates")
+	hybridFlag := flag.String("hybrid", "", "Hybrid mode: mask+wordlist or wordlist+mask (requires -m and -w)")
+	custom1 := flag.String("custom1", "", "Custom charset substituted for ?1 in the mask")
+	custom2 := flag.String("custom2", "", "Custom charset substituted for ?2 in the mask")
+	custom3 := flag.String("custom3", "", "Custom charset substituted for ?3 in the mask")
+	custom4 := flag.String("custom4", "", "Custom charset substituted for ?4 in the mask")
+	shardFlag := flag.String("shard", "", "Claim a contiguous sub-range of the keyspace, as i/N (e.g. 0/4)")
+	checkpointFlag := flag.String("checkpoint", "", "Write periodic progress checkpoints to this file")
+	checkpointIntervalFlag := flag.Duration("checkpoint-interval", 5*time.Second, "How often to write the checkpoint file")
+	resumeFlag := flag.String("resume", "", "Resume a killed run from a checkpoint file written by -checkpoint")
+	coordinatorFlag := flag.String("coordinator", "", "Run as an HTTP coordinator on this address instead of cracking (e.g. :8080)")
+	coordinatorURLFlag := flag.String("coordinator-url", "", "Pull keyspace chunks from a coordinator at this URL instead of -shard")
+	chunkSizeFlag := flag.Uint64("chunk-size", 1_000_000, "Chunk size to request from -coordinator-url")
+	fixFlag := flag.Bool("fix", false, "Rewrite a Reed-Solomon-protected hash file with freshly re-encoded shards after correcting bit-rot")
+	deviceFlag := flag.String("device", "cpu", "Executor to run the KDF on: cpu, gpu, or auto")
+	batchFlag := flag.Int("batch", 1000, "Candidate passwords per executor dispatch")
+	benchFlag := flag.Bool("bench", false, "Benchmark every executor at -N/-r/-P/-l and exit, ignoring <hash_file>")
+	benchN := flag.Int("N", 16384, "scrypt N, used with -bench")
+	benchR := flag.Int("r", 8, "scrypt r, used with -bench")
+	benchP := flag.Int("P", 1, "scrypt p, used with -bench")
+	benchKeyLen := flag.Int("l", 32, "Key length in bytes, used with -bench")
+	benchCount := flag.Int("bench-count", 2000, "Number of synthetic passwords to hash per executor with -bench")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	if *benchFlag {
+		lab2.RunBenchmark(ctx, lab2.Params{Algo: "scrypt", N: *benchN, R: *benchR, P: *benchP, KeyLen: *benchKeyLen}, *batchFlag, *benchCount)
+		return
+	}
+
+	custom := customCharsets{*custom1, *custom2, *custom3, *custom4}
+
+	if *coordinatorFlag != "" {
+		if *maskFlag == "" {
+			fmt.Fprintln(os.Stderr, "Error: -m <mask> is required alongside -coordinator")
+			os.Exit(1)
+		}
+		charsets, err := parseMask(*maskFlag, custom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := runCoordinator(*coordinatorFlag, maskCombinations(charsets)); err != nil {
+			fmt.Fprintf(os.Stderr, "Coordinator error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if (*maskFlag == "" && *wordlistFlag == "") || flag.NArg() < 1 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	data, corrected, err := parseFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing file: %v\n", err)
+		os.Exit(1)
+	}
+	if corrected > 0 {
+		fmt.Fprintf(os.Stderr, "warning: corrected %d bit-rotted share(s) in %s\n", corrected, flag.Arg(0))
+		if *fixFlag {
+			fixed, err := lab2.EncodeRSFile(data.Params, data.Salt, data.Hash)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: -fix failed to re-encode: %v\n", err)
+			} else if err := os.WriteFile(flag.Arg(0), fixed, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: -fix failed to rewrite %s: %v\n", flag.Arg(0), err)
+			} else {
+				fmt.Fprintf(os.Stderr, "-fix: rewrote %s with freshly re-encoded shards\n", flag.Arg(0))
+			}
+		}
+	}
+
+	fmt.Printf("Scrypt bruteforce\n")
+	fmt.Printf("=================\n")
+
+	var current lab2.Params
+	switch data.Params.Algo {
+	case "argon2id":
+		fmt.Printf("Parameters: algo=argon2id, m=%dKiB, t=%d, p=%d\n", data.Params.MemKiB, data.Params.Time, data.Params.P)
+		current = lab2.Params{Algo: "argon2id", MemKiB: 65536, Time: 1, P: runtime.NumCPU(), KeyLen: 32}
+	default:
+		fmt.Printf("Parameters: N=%d, r=%d, p=%d\n", data.Params.N, data.Params.R, data.Params.P)
+		current = lab2.Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: 32}
+	}
+
+	if lab2.NeedsRehash(data.Params, current) {
+		if current.Algo == "argon2id" {
+			fmt.Fprintf(os.Stderr, "warning: hash file uses weaker parameters than the current policy (m=%dKiB, t=%d, p=%d); rehash recommended once cracked\n",
+				current.MemKiB, current.Time, current.P)
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: hash file uses weaker parameters than the current policy (N=%d, r=%d, p=%d); rehash recommended once cracked\n",
+				current.N, current.R, current.P)
+		}
+	}
+	fmt.Printf("CPU cores: %d\n\n", runtime.NumCPU())
+
+	exec, err := lab2.SelectExecutor(*deviceFlag, data.Salt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Executor: %s (batch size %d)\n\n", *deviceFlag, *batchFlag)
+
+	start := time.Now()
+	var pwd string
+	var tried uint64
+	var found bool
+
+	switch {
+	case *hybridFlag != "":
+		if *maskFlag == "" || *wordlistFlag == "" {
+			fmt.Fprintln(os.Stderr, "Error: -hybrid requires both -m and -w")
+			os.Exit(1)
+		}
+		charsets, err := parseMask(*maskFlag, custom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		words, err := loadWordlist(*wordlistFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading wordlist: %v\n", err)
+			os.Exit(1)
+		}
+		maskTotal := maskCombinations(charsets)
+		total := maskTotal * uint64(len(words))
+
+		var src Source
+		switch *hybridFlag {
+		case "mask+wordlist":
+			src = newHybridSource(&maskSource{charsets: charsets, end: maskTotal}, func() Source {
+				return &wordlistSource{words: words}
+			})
+		case "wordlist+mask":
+			src = newHybridSource(&wordlistSource{words: words}, func() Source {
+				return &maskSource{charsets: charsets, end: maskTotal}
+			})
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown -hybrid mode %q, expected mask+wordlist or wordlist+mask\n", *hybridFlag)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Hybrid mode: %s (mask %s x %d words = %d combinations)\n\n", *hybridFlag, *maskFlag, len(words), total)
+		pwd, tried, found = crackWithSource(ctx, data, exec, src, total, *batchFlag)
+
+	case *wordlistFlag != "":
+		words, err := loadWordlist(*wordlistFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading wordlist: %v\n", err)
+			os.Exit(1)
+		}
+
+		var src Source
+		var total uint64
+		if *rulesFlag != "" {
+			rules, err := loadRules(*rulesFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading rules: %v\n", err)
+				os.Exit(1)
+			}
+			src = newRuleSource(words, rules)
+			total = uint64(len(words)) * ruleVariantsPerWord(rules)
+			fmt.Printf("Wordlist mode: %d words x %d rule variants = %d combinations\n\n", len(words), ruleVariantsPerWord(rules), total)
+		} else {
+			src = &wordlistSource{words: words}
+			total = uint64(len(words))
+			fmt.Printf("Wordlist mode: %d words\n\n", total)
+		}
+
+		pwd, tried, found = crackWithSource(ctx, data, exec, src, total, *batchFlag)
+
+	default:
+		charsets, err := parseMask(*maskFlag, custom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		total := maskCombinations(charsets)
+		fmt.Printf("Mask: %s\n", *maskFlag)
+		fmt.Printf("Total combinations: %d\n\n", total)
+
+		if *coordinatorURLFlag != "" {
+			runDistributedClient(ctx, data, exec, charsets, *maskFlag, *coordinatorURLFlag, *chunkSizeFlag, total, *batchFlag)
+			return
+		}
+
+		rangeStart, rangeEnd := uint64(0), total
+		switch {
+		case *resumeFlag != "":
+			cp, err := loadCheckpoint(*resumeFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading checkpoint: %v\n", err)
+				os.Exit(1)
+			}
+			if cp.Mask != *maskFlag {
+				fmt.Fprintf(os.Stderr, "Error: checkpoint was for mask %q, not %q\n", cp.Mask, *maskFlag)
+				os.Exit(1)
+			}
+			rangeStart, rangeEnd = cp.Next, cp.End
+			fmt.Printf("Resuming from checkpoint: index %d of range [%d, %d)\n\n", rangeStart, cp.Start, cp.End)
+
+		case *shardFlag != "":
+			idx, count, err := parseShard(*shardFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			rangeStart, rangeEnd = shardRange(total, idx, count)
+			fmt.Printf("Shard %d/%d: indices [%d, %d)\n\n", idx, count, rangeStart, rangeEnd)
+		}
+
+		pwd, tried, found = crackRange(ctx, data, exec, charsets, *maskFlag, rangeStart, rangeEnd, total, *batchFlag, *checkpointFlag, *checkpointIntervalFlag)
+	}
+
+	if found {
+		fmt.Printf("\n\nPASSWORD FOUND: %s\n", pwd)
+		fmt.Printf("Attempts: %d\n", tried)
+	} else {
+		fmt.Printf("\n\nPASSWORD NOT FOUND\n")
+		fmt.Printf("Tried: %d passwords\n", tried)
+	}
+	fmt.Printf("Time: %.2f seconds\n", time.Since(start).Seconds())
+}
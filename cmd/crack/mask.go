@@ -0,0 +1,48 @@
+package main
+
+import "fmt"
+
+// customCharsets holds the user-defined --custom1..--custom4 charsets
+// substituted for ?1-?4 in a mask.
+type customCharsets [4]string
+
+// parseMask compiles a hashcat-style mask into its per-position charsets:
+// ?l ?u ?d ?s ?a are the built-in classes, ?1-?4 substitute a
+// user-defined custom charset, ?? is a literal '?', and any other
+// character is a literal that fixes that position (e.g. "Pass?d?d" tries
+// "Pass" followed by two digits).
+func parseMask(mask string, custom customCharsets) ([]string, error) {
+	runes := []rune(mask)
+	charsets := make([]string, 0, len(runes))
+
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		if ch != '?' {
+			charsets = append(charsets, string(ch))
+			continue
+		}
+
+		if i+1 >= len(runes) {
+			return nil, fmt.Errorf("mask ends with a dangling '?'")
+		}
+		i++
+		class := runes[i]
+
+		switch {
+		case class == '?':
+			charsets = append(charsets, "?")
+		case class == 'l' || class == 'u' || class == 'd' || class == 's' || class == 'a':
+			charsets = append(charsets, getCharset(class))
+		case class >= '1' && class <= '4':
+			set := custom[class-'1']
+			if set == "" {
+				return nil, fmt.Errorf("mask uses ?%c but --custom%c was not provided", class, class)
+			}
+			charsets = append(charsets, set)
+		default:
+			return nil, fmt.Errorf("unknown mask class ?%c", class)
+		}
+	}
+
+	return charsets, nil
+}
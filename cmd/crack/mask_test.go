@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMaskBuiltins(t *testing.T) {
+	charsets, err := parseMask("?l?u?d?s?a", customCharsets{})
+	if err != nil {
+		t.Fatalf("parseMask: %v", err)
+	}
+	want := []string{getCharset('l'), getCharset('u'), getCharset('d'), getCharset('s'), getCharset('a')}
+	if !reflect.DeepEqual(charsets, want) {
+		t.Errorf("charsets = %v, want %v", charsets, want)
+	}
+}
+
+func TestParseMaskLiteralsAndEscape(t *testing.T) {
+	charsets, err := parseMask("Pass???d", customCharsets{})
+	if err != nil {
+		t.Fatalf("parseMask: %v", err)
+	}
+	want := []string{"P", "a", "s", "s", "?", getCharset('d')} // "Pass" literal, literal '?' via ??, ?d digit class
+	if !reflect.DeepEqual(charsets, want) {
+		t.Errorf("charsets = %v, want %v", charsets, want)
+	}
+}
+
+func TestParseMaskCustomCharsets(t *testing.T) {
+	custom := customCharsets{"xy", "", "", ""}
+	charsets, err := parseMask("?1", custom)
+	if err != nil {
+		t.Fatalf("parseMask: %v", err)
+	}
+	if !reflect.DeepEqual(charsets, []string{"xy"}) {
+		t.Errorf("charsets = %v, want [xy]", charsets)
+	}
+
+	if _, err := parseMask("?2", custom); err == nil {
+		t.Fatal("parseMask with unset --custom2: want error, got nil")
+	}
+}
+
+func TestParseMaskErrors(t *testing.T) {
+	if _, err := parseMask("abc?", customCharsets{}); err == nil {
+		t.Fatal("parseMask with dangling '?': want error, got nil")
+	}
+	if _, err := parseMask("?x", customCharsets{}); err == nil {
+		t.Fatal("parseMask with unknown class: want error, got nil")
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Source is a candidate-password iterator. Mask mode, wordlist mode, a
+// rule-mutated wordlist, and the mask+wordlist/wordlist+mask hybrids all
+// implement it, so crackWithSource doesn't need to know which mode it is
+// driving.
+type Source interface {
+	// Next returns the next candidate password, or ok=false once the
+	// source is exhausted.
+	Next() (password string, ok bool)
+}
+
+// maskSource iterates the mask keyspace [idx, end) in index order.
+type maskSource struct {
+	charsets []string
+	idx, end uint64
+}
+
+func (s *maskSource) Next() (string, bool) {
+	if s.idx >= s.end {
+		return "", false
+	}
+	pw := indexToPassword(s.charsets, s.idx)
+	s.idx++
+	return pw, true
+}
+
+// wordlistSource iterates a pre-loaded word list in order.
+type wordlistSource struct {
+	words []string
+	i     int
+}
+
+func (s *wordlistSource) Next() (string, bool) {
+	if s.i >= len(s.words) {
+		return "", false
+	}
+	w := s.words[s.i]
+	s.i++
+	return w, true
+}
+
+// loadWordlist reads one word per line from path, skipping blank lines.
+func loadWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+		if line == "" {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// loadRules reads one rule per line from path, skipping blank lines and
+// "#"-prefixed comments.
+func loadRules(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, line)
+	}
+	return rules, scanner.Err()
+}
+
+// applyRule mutates word according to a single John/hashcat-style rule:
+//
+//	l     lowercase the whole word
+//	u     uppercase the whole word
+//	c     capitalize (uppercase first letter, lowercase the rest)
+//	r     reverse the word
+//	d     duplicate the word (e.g. "pass" -> "passpass")
+//	$X    append character X
+//	^X    prepend character X
+//	sXY   substitute every X with Y
+func applyRule(rule, word string) (string, error) {
+	if rule == "" {
+		return word, nil
+	}
+
+	switch rule[0] {
+	case 'l':
+		return strings.ToLower(word), nil
+	case 'u':
+		return strings.ToUpper(word), nil
+	case 'c':
+		if word == "" {
+			return word, nil
+		}
+		return strings.ToUpper(word[:1]) + strings.ToLower(word[1:]), nil
+	case 'r':
+		runes := []rune(word)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	case 'd':
+		return word + word, nil
+	case '$':
+		if len(rule) != 2 {
+			return "", fmt.Errorf("rule %q must be $X", rule)
+		}
+		return word + string(rule[1]), nil
+	case '^':
+		if len(rule) != 2 {
+			return "", fmt.Errorf("rule %q must be ^X", rule)
+		}
+		return string(rule[1]) + word, nil
+	case 's':
+		if len(rule) != 3 {
+			return "", fmt.Errorf("rule %q must be sXY", rule)
+		}
+		return strings.ReplaceAll(word, string(rule[1]), string(rule[2])), nil
+	default:
+		return "", fmt.Errorf("unknown rule %q", rule)
+	}
+}
+
+// ruleSource applies every rule in rules to every word in words,
+// yielding the unmodified word first and then one candidate per rule.
+type ruleSource struct {
+	words []string
+	rules []string
+	wi    int
+	ri    int // -1 means "emit the bare word next"
+}
+
+func newRuleSource(words, rules []string) *ruleSource {
+	return &ruleSource{words: words, rules: rules, ri: -1}
+}
+
+func (s *ruleSource) Next() (string, bool) {
+	for {
+		if s.wi >= len(s.words) {
+			return "", false
+		}
+		word := s.words[s.wi]
+
+		if s.ri == -1 {
+			s.ri = 0
+			return word, true
+		}
+		if s.ri < len(s.rules) {
+			rule := s.rules[s.ri]
+			s.ri++
+			out, err := applyRule(rule, word)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: skipping rule: %v\n", err)
+				continue
+			}
+			return out, true
+		}
+
+		s.wi++
+		s.ri = -1
+	}
+}
+
+// ruleVariantsPerWord is the number of candidates newRuleSource produces
+// per input word: the bare word plus one per rule. A rule that applyRule
+// rejects yields no candidate, so with invalid rules this is an upper
+// bound.
+func ruleVariantsPerWord(rules []string) uint64 {
+	return uint64(len(rules)) + 1
+}
+
+// hybridSource concatenates every candidate from outer with every
+// candidate from a fresh instance of inner, implementing hashcat's
+// mask+wordlist and wordlist+mask hybrid modes (innerFactory is called
+// once per outer candidate so inner restarts from the beginning each
+// time).
+type hybridSource struct {
+	outer        Source
+	innerFactory func() Source
+
+	inner     Source
+	curOuter  string
+	haveOuter bool
+}
+
+func newHybridSource(outer Source, innerFactory func() Source) *hybridSource {
+	return &hybridSource{outer: outer, innerFactory: innerFactory}
+}
+
+func (s *hybridSource) Next() (string, bool) {
+	for {
+		if !s.haveOuter {
+			o, ok := s.outer.Next()
+			if !ok {
+				return "", false
+			}
+			s.curOuter = o
+			s.inner = s.innerFactory()
+			s.haveOuter = true
+		}
+
+		in, ok := s.inner.Next()
+		if !ok {
+			s.haveOuter = false
+			continue
+		}
+		return s.curOuter + in, true
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import "testing"
+
+func TestApplyRule(t *testing.T) {
+	cases := []struct {
+		rule, word, want string
+	}{
+		{"l", "PaSS", "pass"},
+		{"u", "PaSS", "PASS"},
+		{"c", "pASS", "Pass"},
+		{"r", "pass", "ssap"},
+		{"d", "pass", "passpass"},
+		{"$1", "pass", "pass1"},
+		{"^1", "pass", "1pass"},
+		{"sxo", "pass", "pass"},
+		{"sas", "pass", "psss"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.rule, func(t *testing.T) {
+			got, err := applyRule(c.rule, c.word)
+			if err != nil {
+				t.Fatalf("applyRule(%q, %q): %v", c.rule, c.word, err)
+			}
+			if got != c.want {
+				t.Errorf("applyRule(%q, %q) = %q, want %q", c.rule, c.word, got, c.want)
+			}
+		})
+	}
+}
+
+func TestApplyRuleErrors(t *testing.T) {
+	if _, err := applyRule("$", "pass"); err == nil {
+		t.Fatal("applyRule($): want error, got nil")
+	}
+	if _, err := applyRule("^", "pass"); err == nil {
+		t.Fatal("applyRule(^): want error, got nil")
+	}
+	if _, err := applyRule("sa", "pass"); err == nil {
+		t.Fatal("applyRule(sa): want error, got nil")
+	}
+	if _, err := applyRule("z", "pass"); err == nil {
+		t.Fatal("applyRule(z): want error, got nil")
+	}
+}
+
+func TestRuleSourceEmitsBareWordThenRules(t *testing.T) {
+	src := newRuleSource([]string{"pass", "word"}, []string{"u", "d"})
+
+	var got []string
+	for {
+		pw, ok := src.Next()
+		if !ok {
+			break
+		}
+		got = append(got, pw)
+	}
+
+	want := []string{"pass", "PASS", "passpass", "word", "WORD", "wordword"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("candidate %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRuleSourceSkipsInvalidRule(t *testing.T) {
+	src := newRuleSource([]string{"pass"}, []string{"z", "u"})
+
+	var got []string
+	for {
+		pw, ok := src.Next()
+		if !ok {
+			break
+		}
+		got = append(got, pw)
+	}
+
+	want := []string{"pass", "PASS"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("candidate %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHybridSourceConcatenatesEveryPair(t *testing.T) {
+	outer := &wordlistSource{words: []string{"a", "b"}}
+	src := newHybridSource(outer, func() Source {
+		return &wordlistSource{words: []string{"1", "2"}}
+	})
+
+	var got []string
+	for {
+		pw, ok := src.Next()
+		if !ok {
+			break
+		}
+		got = append(got, pw)
+	}
+
+	want := []string{"a1", "a2", "b1", "b2"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("candidate %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
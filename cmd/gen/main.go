@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/LyashenkoAndron/scrypt/lab2"
+)
+
+func generateRandom(size int) []byte {
+	b := make([]byte, size)
+	rand.Read(b)
+	return b
+}
+
+func main() {
+	password := flag.String("pass", "", "Password to hash")
+	output := flag.String("o", "", "Output file (optional)")
+	algo := flag.String("algo", "scrypt", "KDF to use: scrypt or argon2id")
+	N := flag.Int("N", 16384, "scrypt CPU/memory cost parameter (must be power of 2)")
+	r := flag.Int("r", 8, "scrypt block size parameter")
+	p := flag.Int("P", 1, "parallelization parameter")
+	memKiB := flag.Int("m", 64*1024, "argon2id memory cost in KiB")
+	t := flag.Int("t", 1, "argon2id iteration count")
+	keyLen := flag.Int("l", 32, "Desired key length in bytes")
+	legacy := flag.Bool("legacy", false, "Emit the legacy N*r*p*keyLen*salt*hash text format instead of a PHC string (scrypt only)")
+	crypt7 := flag.Bool("crypt7", false, "Emit a $7$ crypt-style string instead of a PHC string (scrypt only)")
+	rs := flag.Bool("rs", false, "Emit the Reed-Solomon-protected binary envelope instead of a PHC string (scrypt only, keyLen must be 32)")
+	auto := flag.Bool("auto", false, "Auto-calibrate cost parameters to hit -target wall-clock time on this machine")
+	targetStr := flag.String("target", "250ms", "Target wall-clock time for -auto calibration")
+	flag.Parse()
+
+	if *password == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s -pass <password> [-algo scrypt|argon2id] [-auto] [-o file]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nParameters:\n")
+		fmt.Fprintf(os.Stderr, "  -pass   : Password to hash (required)\n")
+		fmt.Fprintf(os.Stderr, "  -algo   : KDF to use: scrypt or argon2id (default: scrypt)\n")
+		fmt.Fprintf(os.Stderr, "  -N      : scrypt CPU/memory cost (default: 16384, must be power of 2)\n")
+		fmt.Fprintf(os.Stderr, "  -r      : scrypt block size (default: 8)\n")
+		fmt.Fprintf(os.Stderr, "  -P      : parallelization (default: 1)\n")
+		fmt.Fprintf(os.Stderr, "  -m      : argon2id memory cost in KiB (default: 65536)\n")
+		fmt.Fprintf(os.Stderr, "  -t      : argon2id iteration count (default: 1)\n")
+		fmt.Fprintf(os.Stderr, "  -l      : Key length in bytes (default: 32)\n")
+		fmt.Fprintf(os.Stderr, "  -o      : Output file (optional, prints to stdout if not specified)\n")
+		fmt.Fprintf(os.Stderr, "  -legacy : Emit the legacy N*r*p*keyLen*salt*hash format (scrypt only)\n")
+		fmt.Fprintf(os.Stderr, "  -crypt7 : Emit a $7$ crypt-style string (scrypt only)\n")
+		fmt.Fprintf(os.Stderr, "  -rs     : Emit the Reed-Solomon-protected binary envelope (scrypt only, keyLen must be 32)\n")
+		fmt.Fprintf(os.Stderr, "  -auto   : Auto-calibrate cost parameters to hit -target\n")
+		fmt.Fprintf(os.Stderr, "  -target : Target wall-clock time for -auto (default: 250ms)\n")
+		os.Exit(1)
+	}
+
+	if *algo != "scrypt" && *algo != "argon2id" {
+		fmt.Fprintf(os.Stderr, "Error: unknown -algo %q, expected scrypt or argon2id\n", *algo)
+		os.Exit(1)
+	}
+
+	params := lab2.Params{Algo: *algo, N: *N, R: *r, MemKiB: *memKiB, Time: *t, P: *p, KeyLen: *keyLen}
+
+	if *auto {
+		target, err := time.ParseDuration(*targetStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -target %q: %v\n", *targetStr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Calibrating %s parameters for a %s target...\n", *algo, target)
+		params, err = lab2.Calibrate(*algo, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error calibrating parameters: %v\n", err)
+			os.Exit(1)
+		}
+		params.KeyLen = *keyLen
+	}
+
+	if (*legacy || *crypt7 || *rs) && params.Algo != "scrypt" {
+		fmt.Fprintf(os.Stderr, "Error: -legacy, -crypt7, and -rs only support scrypt hashes\n")
+		os.Exit(1)
+	}
+
+	salt := generateRandom(16)
+
+	fmt.Fprintf(os.Stderr, "Generating %s hash...\n", *algo)
+	if *algo == "argon2id" {
+		fmt.Fprintf(os.Stderr, "Parameters: m=%d, t=%d, p=%d, keyLen=%d\n", params.MemKiB, params.Time, params.P, params.KeyLen)
+	} else {
+		fmt.Fprintf(os.Stderr, "Parameters: N=%d, r=%d, p=%d, keyLen=%d\n", params.N, params.R, params.P, params.KeyLen)
+	}
+
+	hashValue, err := lab2.ComputeHash(*password, salt, params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing hash: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *rs {
+		envelope, err := lab2.EncodeRSFile(params, salt, hashValue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding hash: %v\n", err)
+			os.Exit(1)
+		}
+		if *output == "" {
+			fmt.Fprintf(os.Stderr, "Error: -rs produces a binary file and requires -o\n")
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*output, envelope, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Hash saved to: %s\n", *output)
+		return
+	}
+
+	var result string
+	switch {
+	case *legacy:
+		result = fmt.Sprintf("%d*%d*%d*%d*%x*%x", params.N, params.R, params.P, params.KeyLen, salt, hashValue)
+	case *crypt7:
+		result, err = lab2.MarshalCrypt7(params, salt, hashValue)
+	default:
+		result, err = lab2.Marshal(params, salt, hashValue)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding hash: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output != "" {
+		err := os.WriteFile(*output, []byte(result), 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Hash saved to: %s\n", *output)
+	} else {
+		fmt.Println(result)
+	}
+}
@@ -0,0 +1,49 @@
+package lab2
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RunBenchmark hashes count synthetic passwords through every available
+// executor at params and reports pwd/s, so users can pick -device before
+// committing to a real run.
+func RunBenchmark(ctx context.Context, params Params, batchSize, count int) {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	passwords := make([]string, count)
+	for i := range passwords {
+		passwords[i] = fmt.Sprintf("benchmark-candidate-%d", i)
+	}
+
+	fmt.Printf("Benchmarking N=%d, r=%d, p=%d, keyLen=%d (%d passwords, batch size %d)\n\n",
+		params.N, params.R, params.P, params.KeyLen, count, batchSize)
+
+	benchExecutor("cpu", newCPUExecutor(salt), ctx, passwords, params, batchSize)
+
+	if gpuExec, err := newGPUExecutor(salt); err == nil {
+		benchExecutor("gpu", gpuExec, ctx, passwords, params, batchSize)
+	} else {
+		fmt.Fprintf(os.Stderr, "gpu: skipped (%v)\n", err)
+	}
+}
+
+func benchExecutor(name string, exec Executor, ctx context.Context, passwords []string, params Params, batchSize int) {
+	start := time.Now()
+	for i := 0; i < len(passwords); i += batchSize {
+		end := i + batchSize
+		if end > len(passwords) {
+			end = len(passwords)
+		}
+		if _, err := exec.Run(ctx, passwords[i:end], params); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", name, err)
+			return
+		}
+	}
+	elapsed := time.Since(start)
+	speed := float64(len(passwords)) / elapsed.Seconds()
+	fmt.Printf("%-4s: %d passwords in %.2fs = %.1f pwd/s\n", name, len(passwords), elapsed.Seconds(), speed)
+}
@@ -0,0 +1,83 @@
+package lab2
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Executor computes KDF hashes for a batch of candidate passwords under a
+// fixed target salt and set of params. Batching lets an executor amortize
+// per-dispatch overhead; cpuExecutor, the only implementation, fans the
+// batch out across a worker pool.
+type Executor interface {
+	// Run returns one hash per password, in the same order as passwords.
+	Run(ctx context.Context, passwords []string, params Params) ([][]byte, error)
+}
+
+// cpuExecutor hashes a batch across a worker pool sized to the number of
+// CPU cores. It's the default and always available.
+type cpuExecutor struct {
+	salt []byte
+}
+
+func newCPUExecutor(salt []byte) *cpuExecutor {
+	return &cpuExecutor{salt: salt}
+}
+
+func (e *cpuExecutor) Run(ctx context.Context, passwords []string, params Params) ([][]byte, error) {
+	out := make([][]byte, len(passwords))
+	errs := make([]error, len(passwords))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i, pw := range passwords {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, pw string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out[i], errs[i] = ComputeHash(pw, e.salt, params)
+		}(i, pw)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, ctx.Err()
+}
+
+// gpuAvailable reports whether this binary was built with `-tags gpu`,
+// which links executor_gpu.go's newGPUExecutor, an OpenCL device probe
+// that also returns an error in every case. Without that tag,
+// executor_nogpu.go's newGPUExecutor always errors. Building with `gpu` alone is not enough: github.com/rainliu/gocl/cl
+// itself gates its OpenCL bindings behind one of `cl11`, `cl12`, or
+// `cl20` (matching the OpenCL SDK headers/lib installed on the build
+// machine), so a GPU build needs both, e.g. `-tags "gpu cl12"`.
+var gpuAvailable = gpuAvailableBuild
+
+// SelectExecutor builds the Executor for -device: "cpu" always uses
+// cpuExecutor. "gpu" always returns an error, because newGPUExecutor
+// never returns an executor, with or without a GPU build and device.
+// "auto" tries the GPU and therefore always ends up with cpuExecutor.
+func SelectExecutor(device string, salt []byte) (Executor, error) {
+	switch device {
+	case "cpu":
+		return newCPUExecutor(salt), nil
+	case "gpu":
+		return newGPUExecutor(salt)
+	case "auto":
+		if gpuAvailable {
+			if exec, err := newGPUExecutor(salt); err == nil {
+				return exec, nil
+			}
+		}
+		return newCPUExecutor(salt), nil
+	default:
+		return nil, fmt.Errorf("unknown -device %q, expected cpu, gpu, or auto", device)
+	}
+}
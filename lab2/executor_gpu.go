@@ -0,0 +1,34 @@
+//go:build gpu
+
+package lab2
+
+// Building this file requires not just `-tags gpu` but also one of
+// gocl/cl's own OpenCL version tags (`cl11`, `cl12`, `cl20`) matching the
+// OpenCL SDK installed on the build machine, e.g. `-tags "gpu cl12"` —
+// without one of those, gocl/cl itself has nothing compiled in and
+// cl.GetPlatforms below won't resolve.
+
+import (
+	"fmt"
+
+	"github.com/rainliu/gocl/cl"
+)
+
+const gpuAvailableBuild = true
+
+// newGPUExecutor probes for an OpenCL GPU device and always returns an
+// error: this package has no scrypt OpenCL kernel, so even when a GPU
+// device is found it returns a "not implemented" error and no Executor.
+// -device gpu therefore fails and -device auto falls back to the CPU.
+func newGPUExecutor(salt []byte) (Executor, error) {
+	platforms, err := cl.GetPlatforms()
+	if err != nil || len(platforms) == 0 {
+		return nil, fmt.Errorf("gpu executor: no OpenCL platforms found: %v", err)
+	}
+	devices, err := platforms[0].GetDevices(cl.DeviceTypeGPU)
+	if err != nil || len(devices) == 0 {
+		return nil, fmt.Errorf("gpu executor: no OpenCL GPU devices found: %v", err)
+	}
+
+	return nil, fmt.Errorf("gpu executor: found an OpenCL GPU device, but the scrypt OpenCL kernel is not implemented; use -device cpu")
+}
@@ -0,0 +1,16 @@
+//go:build !gpu
+
+package lab2
+
+import "fmt"
+
+const gpuAvailableBuild = false
+
+// newGPUExecutor is a stub in binaries built without `-tags gpu`. Build
+// with that tag plus the gocl OpenCL version tag matching the installed
+// SDK (`cl11`, `cl12`, or `cl20`) for executor_gpu.go's OpenCL device
+// probe, e.g. `go build -tags "gpu cl12" ./cmd/crack`; that version also
+// returns an error, as it has no scrypt kernel to run.
+func newGPUExecutor(salt []byte) (Executor, error) {
+	return nil, fmt.Errorf("gpu executor: binary was built without -tags gpu")
+}
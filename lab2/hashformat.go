@@ -0,0 +1,253 @@
+package lab2
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Params holds the cost parameters that get embedded in a hash string.
+// N/R apply to scrypt; MemKiB/Time apply to argon2id; P and KeyLen are
+// shared by both.
+type Params struct {
+	Algo   string // "scrypt" or "argon2id"; "" is treated as "scrypt"
+	N      int
+	R      int
+	MemKiB int
+	Time   int
+	P      int
+	KeyLen int
+}
+
+// Decoded is a parsed password hash, regardless of which on-disk format
+// it was stored in.
+type Decoded struct {
+	Format string // "phc", "crypt7", "legacy" or "rs"
+	Params Params
+	Salt   []byte
+	Hash   []byte
+}
+
+const (
+	scryptPrefix   = "$scrypt$"
+	argon2idPrefix = "$argon2id$"
+	crypt7Prefix   = "$7$"
+)
+
+var b64 = base64.RawStdEncoding
+
+// Marshal encodes params/salt/hash as a PHC string. For scrypt:
+//
+//	$scrypt$ln=<log2N>,r=<r>,p=<p>$<b64 salt>$<b64 hash>
+//
+// For argon2id:
+//
+//	$argon2id$m=<memKiB>,t=<time>,p=<p>$<b64 salt>$<b64 hash>
+func Marshal(params Params, salt, hash []byte) (string, error) {
+	switch params.Algo {
+	case "", "scrypt":
+		logN, err := log2N(params.N)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%sln=%d,r=%d,p=%d$%s$%s",
+			scryptPrefix, logN, params.R, params.P,
+			b64.EncodeToString(salt), b64.EncodeToString(hash)), nil
+	case "argon2id":
+		return fmt.Sprintf("%sm=%d,t=%d,p=%d$%s$%s",
+			argon2idPrefix, params.MemKiB, params.Time, params.P,
+			b64.EncodeToString(salt), b64.EncodeToString(hash)), nil
+	default:
+		return "", fmt.Errorf("hashformat: unknown algorithm %q", params.Algo)
+	}
+}
+
+// MarshalCrypt7 encodes params/salt/hash as a "$7$" crypt-style string,
+// for tools that expect a crypt(3)-shaped identifier instead of a PHC
+// string. Note this is this tool's own "$7$<ln>$<r>$<p>$<salt>$<hash>" layout, not
+// the libsodium/Passlib scryptsalsa208sha256 $7$ encoding (which packs
+// N/r/p as base64 without field separators) — it round-trips with Parse
+// but isn't byte-compatible with those libraries.
+func MarshalCrypt7(params Params, salt, hash []byte) (string, error) {
+	logN, err := log2N(params.N)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%02d$%d$%d$%s$%s",
+		crypt7Prefix, logN, params.R, params.P,
+		b64.EncodeToString(salt), b64.EncodeToString(hash)), nil
+}
+
+// Parse detects which format s is in (scrypt PHC, argon2id PHC, crypt7,
+// or the legacy N*r*p*keyLen*salt*hash text format) and decodes it.
+func Parse(s string) (*Decoded, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, scryptPrefix):
+		return parseScryptPHC(s)
+	case strings.HasPrefix(s, argon2idPrefix):
+		return parseArgon2idPHC(s)
+	case strings.HasPrefix(s, crypt7Prefix):
+		return parseCrypt7(s)
+	default:
+		return parseLegacy(s)
+	}
+}
+
+func parseScryptPHC(s string) (*Decoded, error) {
+	fields := strings.Split(strings.TrimPrefix(s, scryptPrefix), "$")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("hashformat: malformed phc string, expected 3 fields, got %d", len(fields))
+	}
+
+	var logN, r, p int
+	n, err := fmt.Sscanf(fields[0], "ln=%d,r=%d,p=%d", &logN, &r, &p)
+	if err != nil || n != 3 {
+		return nil, fmt.Errorf("hashformat: malformed phc params %q: %v", fields[0], err)
+	}
+
+	salt, err := b64.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid salt: %v", err)
+	}
+	hash, err := b64.DecodeString(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid hash: %v", err)
+	}
+
+	return &Decoded{
+		Format: "phc",
+		Params: Params{Algo: "scrypt", N: 1 << logN, R: r, P: p, KeyLen: len(hash)},
+		Salt:   salt,
+		Hash:   hash,
+	}, nil
+}
+
+func parseArgon2idPHC(s string) (*Decoded, error) {
+	fields := strings.Split(strings.TrimPrefix(s, argon2idPrefix), "$")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("hashformat: malformed phc string, expected 3 fields, got %d", len(fields))
+	}
+
+	var memKiB, t, p int
+	n, err := fmt.Sscanf(fields[0], "m=%d,t=%d,p=%d", &memKiB, &t, &p)
+	if err != nil || n != 3 {
+		return nil, fmt.Errorf("hashformat: malformed phc params %q: %v", fields[0], err)
+	}
+
+	salt, err := b64.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid salt: %v", err)
+	}
+	hash, err := b64.DecodeString(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid hash: %v", err)
+	}
+
+	return &Decoded{
+		Format: "phc",
+		Params: Params{Algo: "argon2id", MemKiB: memKiB, Time: t, P: p, KeyLen: len(hash)},
+		Salt:   salt,
+		Hash:   hash,
+	}, nil
+}
+
+func parseCrypt7(s string) (*Decoded, error) {
+	fields := strings.Split(strings.TrimPrefix(s, crypt7Prefix), "$")
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("hashformat: malformed $7$ string, expected 5 fields, got %d", len(fields))
+	}
+
+	logN, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid logN: %v", err)
+	}
+	r, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid r: %v", err)
+	}
+	p, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid p: %v", err)
+	}
+
+	salt, err := b64.DecodeString(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid salt: %v", err)
+	}
+	hash, err := b64.DecodeString(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid hash: %v", err)
+	}
+
+	return &Decoded{
+		Format: "crypt7",
+		Params: Params{Algo: "scrypt", N: 1 << logN, R: r, P: p, KeyLen: len(hash)},
+		Salt:   salt,
+		Hash:   hash,
+	}, nil
+}
+
+func parseLegacy(s string) (*Decoded, error) {
+	parts := strings.Split(s, "*")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("hashformat: invalid legacy format, expected 6 parts, got %d", len(parts))
+	}
+
+	var params Params
+	var err error
+
+	params.N, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid N parameter: %v", err)
+	}
+	params.R, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid r parameter: %v", err)
+	}
+	params.P, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid p parameter: %v", err)
+	}
+	params.KeyLen, err = strconv.Atoi(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid keyLen parameter: %v", err)
+	}
+
+	salt, err := hex.DecodeString(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid salt: %v", err)
+	}
+	hash, err := hex.DecodeString(parts[5])
+	if err != nil {
+		return nil, fmt.Errorf("hashformat: invalid hash: %v", err)
+	}
+
+	params.Algo = "scrypt"
+	return &Decoded{Format: "legacy", Params: params, Salt: salt, Hash: hash}, nil
+}
+
+// NeedsRehash reports whether params are weaker than policy along any
+// axis, mirroring the "preferred-parameters upgrade" pattern from
+// go-passwd: if a stored hash used a different algorithm or lighter cost
+// parameters than the caller's current policy, the verifier should
+// signal that a rehash with stronger params is warranted.
+func NeedsRehash(params, policy Params) bool {
+	if params.Algo != policy.Algo {
+		return true
+	}
+	if params.Algo == "argon2id" {
+		return params.MemKiB < policy.MemKiB || params.Time < policy.Time || params.P < policy.P || params.KeyLen < policy.KeyLen
+	}
+	return params.N < policy.N || params.R < policy.R || params.P < policy.P || params.KeyLen < policy.KeyLen
+}
+
+func log2N(n int) (int, error) {
+	if n <= 0 || n&(n-1) != 0 {
+		return 0, fmt.Errorf("hashformat: N must be a power of two, got %d", n)
+	}
+	return bits.TrailingZeros(uint(n)), nil
+}
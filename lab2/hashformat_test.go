@@ -0,0 +1,121 @@
+package lab2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarshalParseRoundTrip(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	hash := []byte("fedcba9876543210fedcba9876543210")
+
+	cases := []struct {
+		name   string
+		params Params
+	}{
+		{"scrypt", Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: len(hash)}},
+		{"argon2id", Params{Algo: "argon2id", MemKiB: 65536, Time: 1, P: 4, KeyLen: len(hash)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := Marshal(c.params, salt, hash)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			decoded, err := Parse(s)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", s, err)
+			}
+			if decoded.Format != "phc" {
+				t.Errorf("Format = %q, want phc", decoded.Format)
+			}
+			if decoded.Params != c.params {
+				t.Errorf("Params = %+v, want %+v", decoded.Params, c.params)
+			}
+			if !bytes.Equal(decoded.Salt, salt) {
+				t.Errorf("Salt = %x, want %x", decoded.Salt, salt)
+			}
+			if !bytes.Equal(decoded.Hash, hash) {
+				t.Errorf("Hash = %x, want %x", decoded.Hash, hash)
+			}
+		})
+	}
+}
+
+func TestMarshalCrypt7RoundTrip(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	hash := []byte("fedcba9876543210fedcba9876543210")
+	params := Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: len(hash)}
+
+	s, err := MarshalCrypt7(params, salt, hash)
+	if err != nil {
+		t.Fatalf("MarshalCrypt7: %v", err)
+	}
+
+	decoded, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	if decoded.Format != "crypt7" {
+		t.Errorf("Format = %q, want crypt7", decoded.Format)
+	}
+	if decoded.Params != params {
+		t.Errorf("Params = %+v, want %+v", decoded.Params, params)
+	}
+}
+
+func TestParseLegacy(t *testing.T) {
+	salt := []byte{0xde, 0xad, 0xbe, 0xef}
+	hash := []byte{0x01, 0x02, 0x03, 0x04}
+	s := "16384*8*1*4*deadbeef*01020304"
+
+	decoded, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	want := Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: 4}
+	if decoded.Format != "legacy" {
+		t.Errorf("Format = %q, want legacy", decoded.Format)
+	}
+	if decoded.Params != want {
+		t.Errorf("Params = %+v, want %+v", decoded.Params, want)
+	}
+	if !bytes.Equal(decoded.Salt, salt) || !bytes.Equal(decoded.Hash, hash) {
+		t.Errorf("Salt/Hash = %x/%x, want %x/%x", decoded.Salt, decoded.Hash, salt, hash)
+	}
+}
+
+func TestParseLegacyInvalid(t *testing.T) {
+	if _, err := Parse("16384*8*1*4*deadbeef"); err == nil {
+		t.Fatal("Parse with missing field: want error, got nil")
+	}
+	if _, err := Parse("notanumber*8*1*4*deadbeef*01020304"); err == nil {
+		t.Fatal("Parse with non-numeric N: want error, got nil")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	policy := Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: 32}
+
+	cases := []struct {
+		name   string
+		params Params
+		want   bool
+	}{
+		{"equal", policy, false},
+		{"stronger N", Params{Algo: "scrypt", N: 32768, R: 8, P: 1, KeyLen: 32}, false},
+		{"weaker N", Params{Algo: "scrypt", N: 8192, R: 8, P: 1, KeyLen: 32}, true},
+		{"weaker KeyLen", Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: 16}, true},
+		{"different algo", Params{Algo: "argon2id", MemKiB: 65536, Time: 1, P: 1, KeyLen: 32}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := NeedsRehash(c.params, policy); got != c.want {
+				t.Errorf("NeedsRehash(%+v, %+v) = %v, want %v", c.params, policy, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package lab2
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ComputeHash runs the KDF named by params.Algo against password/salt and
+// returns the derived key. It is the single place both the generator and
+// the bruteforcer call into, so the two tools can never disagree about
+// how a given algorithm tag is computed.
+func ComputeHash(password string, salt []byte, params Params) ([]byte, error) {
+	switch params.Algo {
+	case "", "scrypt":
+		return scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.KeyLen)
+	case "argon2id":
+		return argon2.IDKey([]byte(password), salt, uint32(params.Time), uint32(params.MemKiB), uint8(params.P), uint32(params.KeyLen)), nil
+	default:
+		return nil, fmt.Errorf("kdf: unknown algorithm %q", params.Algo)
+	}
+}
+
+// Calibrate ramps an algorithm's cost parameter up in doubling steps
+// until ComputeHash takes longer than target on this machine. If the last
+// step overshoots by more than 2x, argon2id bisects its memory cost
+// between the last two steps; scrypt, whose N must stay a power of two,
+// falls back to the previous N instead. This mirrors the calibration loop
+// luksy's tune.go uses to pick a LUKS iteration count for a target
+// unlock time.
+func Calibrate(algo string, target time.Duration) (Params, error) {
+	switch algo {
+	case "", "scrypt":
+		return calibrateScrypt(target)
+	case "argon2id":
+		return calibrateArgon2id(target)
+	default:
+		return Params{}, fmt.Errorf("kdf: unknown algorithm %q", algo)
+	}
+}
+
+const calibKeyLen = 32
+
+var calibSalt = []byte("kdf-calibration-salt-do-not-use")
+
+// timeScryptFn/timeArgon2idFn are swapped out in tests so the doubling
+// and bisection math in calibrateScrypt/calibrateArgon2id can be
+// exercised with deterministic, instant fake timings instead of running
+// the real (and machine-speed-dependent) KDF.
+var (
+	timeScryptFn   = timeScrypt
+	timeArgon2idFn = timeArgon2id
+)
+
+func timeScrypt(N, r, p int) (time.Duration, error) {
+	start := time.Now()
+	if _, err := scrypt.Key([]byte("kdf-calibration-password"), calibSalt, N, r, p, calibKeyLen); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+func timeArgon2id(memKiB, t, p int) time.Duration {
+	start := time.Now()
+	argon2.IDKey([]byte("kdf-calibration-password"), calibSalt, uint32(t), uint32(memKiB), uint8(p), calibKeyLen)
+	return time.Since(start)
+}
+
+func calibrateScrypt(target time.Duration) (Params, error) {
+	r, p := 8, 1
+	N := 16384
+
+	prevN := N
+	elapsed, err := timeScryptFn(N, r, p)
+	if err != nil {
+		return Params{}, fmt.Errorf("kdf: calibrating scrypt N=%d: %v", N, err)
+	}
+	for elapsed < target {
+		prevN = N
+		N *= 2
+		elapsed, err = timeScryptFn(N, r, p)
+		if err != nil {
+			return Params{}, fmt.Errorf("kdf: calibrating scrypt N=%d: %v", N, err)
+		}
+	}
+
+	if elapsed > 2*target && prevN != N {
+		// N only ever doubles, so there's no power-of-two midpoint to
+		// bisect to: prefer the last value that stayed under target over
+		// one that blew past it by more than 2x.
+		N = prevN
+	}
+
+	return Params{Algo: "scrypt", N: N, R: r, P: p, KeyLen: calibKeyLen}, nil
+}
+
+func calibrateArgon2id(target time.Duration) (Params, error) {
+	t := 1
+	p := runtime.NumCPU()
+	if p < 1 {
+		p = 1
+	} else if p > 255 {
+		// argon2.IDKey takes p as a uint8; clamp so a high-core-count
+		// machine can't silently wrap it (256 -> 0) into a degenerate call.
+		p = 255
+	}
+	memKiB := 64 * 1024
+
+	prevMem := memKiB
+	for timeArgon2idFn(memKiB, t, p) < target {
+		prevMem = memKiB
+		memKiB *= 2
+	}
+
+	elapsed := timeArgon2idFn(memKiB, t, p)
+	if elapsed > 2*target {
+		lo, hi := prevMem, memKiB
+		for hi-lo > lo/10 && hi-lo > 1024 {
+			mid := lo + (hi-lo)/2
+			if timeArgon2idFn(mid, t, p) < target {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		memKiB = hi
+	}
+
+	return Params{Algo: "argon2id", MemKiB: memKiB, Time: t, P: p, KeyLen: calibKeyLen}, nil
+}
@@ -0,0 +1,128 @@
+package lab2
+
+import (
+	"testing"
+	"time"
+)
+
+func withFakeScryptTimer(t *testing.T, fn func(N, r, p int) (time.Duration, error)) {
+	t.Helper()
+	orig := timeScryptFn
+	timeScryptFn = fn
+	t.Cleanup(func() { timeScryptFn = orig })
+}
+
+func withFakeArgon2idTimer(t *testing.T, fn func(memKiB, t, p int) time.Duration) {
+	t.Helper()
+	orig := timeArgon2idFn
+	timeArgon2idFn = fn
+	t.Cleanup(func() { timeArgon2idFn = orig })
+}
+
+func TestCalibrateScryptDoublesUntilTarget(t *testing.T) {
+	// N=16384 -> 10ms, 32768 -> 20ms, 65536 -> 40ms; target 30ms should
+	// stop doubling as soon as elapsed crosses it, without overshooting
+	// past the 2x bisect threshold.
+	withFakeScryptTimer(t, func(N, r, p int) (time.Duration, error) {
+		switch N {
+		case 16384:
+			return 10 * time.Millisecond, nil
+		case 32768:
+			return 20 * time.Millisecond, nil
+		case 65536:
+			return 40 * time.Millisecond, nil
+		default:
+			t.Fatalf("unexpected N=%d", N)
+			return 0, nil
+		}
+	})
+
+	params, err := Calibrate("scrypt", 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if params.N != 65536 {
+		t.Errorf("N = %d, want 65536", params.N)
+	}
+}
+
+func TestCalibrateScryptRevertsOnOvershoot(t *testing.T) {
+	// N=16384 -> 10ms, 32768 -> 200ms: 200ms is more than 2x the 50ms
+	// target, and N only ever doubles (no power-of-two midpoint to
+	// bisect to), so Calibrate should fall back to the last N that
+	// stayed under target instead of returning the 10x-over value.
+	withFakeScryptTimer(t, func(N, r, p int) (time.Duration, error) {
+		switch N {
+		case 16384:
+			return 10 * time.Millisecond, nil
+		case 32768:
+			return 200 * time.Millisecond, nil
+		default:
+			t.Fatalf("unexpected N=%d", N)
+			return 0, nil
+		}
+	})
+
+	params, err := Calibrate("scrypt", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if params.N != 16384 {
+		t.Errorf("N = %d, want 16384 (reverted)", params.N)
+	}
+}
+
+func TestCalibrateScryptNoDoublingNeeded(t *testing.T) {
+	// The baseline N=16384 already exceeds target, so the loop body
+	// never runs and prevN == N: there's nothing to revert to.
+	withFakeScryptTimer(t, func(N, r, p int) (time.Duration, error) {
+		return 5 * time.Millisecond, nil
+	})
+
+	params, err := Calibrate("scrypt", time.Millisecond)
+	if err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if params.N != 16384 {
+		t.Errorf("N = %d, want 16384", params.N)
+	}
+}
+
+func TestCalibrateArgon2idBisectsOnOvershoot(t *testing.T) {
+	// memKiB doubles 64MB -> 128MB -> 256MB under a quadratic fake timer
+	// (10ms, 40ms, 160ms), where 256MB blows well past 2x the 50ms target;
+	// the bisection between 128MB and 256MB should settle strictly
+	// between the two.
+	withFakeArgon2idTimer(t, func(memKiB, ti, p int) time.Duration {
+		ratio := float64(memKiB) / (64 * 1024)
+		return time.Duration(ratio * ratio * float64(10*time.Millisecond))
+	})
+
+	params, err := Calibrate("argon2id", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if params.MemKiB <= 128*1024 || params.MemKiB >= 256*1024 {
+		t.Errorf("MemKiB = %d, want strictly between 131072 and 262144", params.MemKiB)
+	}
+}
+
+func TestCalibrateArgon2idNoDoublingNeeded(t *testing.T) {
+	withFakeArgon2idTimer(t, func(memKiB, ti, p int) time.Duration {
+		return time.Millisecond
+	})
+
+	params, err := Calibrate("argon2id", time.Microsecond)
+	if err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if params.MemKiB != 64*1024 {
+		t.Errorf("MemKiB = %d, want baseline 65536", params.MemKiB)
+	}
+}
+
+func TestCalibrateUnknownAlgo(t *testing.T) {
+	if _, err := Calibrate("bcrypt", time.Millisecond); err == nil {
+		t.Fatal("Calibrate(bcrypt): want error, got nil")
+	}
+}
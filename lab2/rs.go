@@ -0,0 +1,203 @@
+package lab2
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/HACKERALERT/infectious"
+)
+
+// The Reed-Solomon envelope wraps a fixed-width scrypt record so a hash
+// file stored on flaky media can still be parsed after partial
+// corruption, borrowing the FEC approach Picocrypt uses for its
+// critical header fields. Layout:
+//
+//	4 bytes  magic "SCRY"
+//	1 byte   version
+//	15 bytes RS(5,15)  params: logN(1) r(1) p(1) keyLen(2, big-endian)
+//	48 bytes RS(16,48) salt (16 bytes)
+//	96 bytes RS(32,96) hash (32 bytes)
+const (
+	RSMagic   = "SCRY"
+	rsVersion = 1
+
+	rsParamsRequired, rsParamsTotal = 5, 15
+	rsSaltRequired, rsSaltTotal     = 16, 48
+	rsHashRequired, rsHashTotal     = 32, 96
+
+	rsFileSize = len(RSMagic) + 1 + rsParamsTotal + rsSaltTotal + rsHashTotal
+)
+
+// rsEncode splits data into rsRequired systematic shares and produces
+// rsTotal total shares (systematic + parity), one byte per share,
+// concatenated in share-number order.
+func rsEncode(data []byte, required, total int) ([]byte, error) {
+	if len(data) != required {
+		return nil, fmt.Errorf("rsEncode: data is %d bytes, want %d", len(data), required)
+	}
+	fec, err := infectious.NewFEC(required, total)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, total)
+	err = fec.Encode(data, func(s infectious.Share) {
+		out[s.Number] = s.Data[0]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// rsDecode takes `total` possibly bit-rotted share bytes and recovers
+// the original `required`-byte payload, correcting as many corrupted
+// shares as the code's redundancy allows. It returns how many shares it
+// had to correct, as a warning count for the caller to surface.
+func rsDecode(encoded []byte, required, total int) (data []byte, corrected int, err error) {
+	if len(encoded) != total {
+		return nil, 0, fmt.Errorf("rsDecode: encoded is %d bytes, want %d", len(encoded), total)
+	}
+	fec, err := infectious.NewFEC(required, total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	shares := make([]infectious.Share, total)
+	for i, b := range encoded {
+		shares[i] = infectious.Share{Number: i, Data: []byte{b}}
+	}
+
+	// Correct mutates shares in place (and may reorder them), rather than
+	// returning a corrected copy.
+	if err := fec.Correct(shares); err != nil {
+		return nil, 0, fmt.Errorf("rsDecode: uncorrectable: %v", err)
+	}
+
+	out := make([]byte, required)
+	for _, s := range shares {
+		if s.Data[0] != encoded[s.Number] {
+			corrected++
+		}
+		if s.Number < required {
+			out[s.Number] = s.Data[0]
+		}
+	}
+	return out, corrected, nil
+}
+
+// packParams packs N/R/P/KeyLen into the 5-byte params section. N must be
+// a power of two (logN is stored, not N itself) and R/P must each fit in
+// a byte; silently truncating either would encode the wrong scrypt
+// parameters into the envelope, permanently breaking verification
+// against the hash that was actually computed.
+func packParams(p Params) ([]byte, error) {
+	logN, err := log2N(p.N)
+	if err != nil {
+		return nil, err
+	}
+	if p.R < 0 || p.R > 255 {
+		return nil, fmt.Errorf("packParams: r=%d does not fit in a byte (0-255)", p.R)
+	}
+	if p.P < 0 || p.P > 255 {
+		return nil, fmt.Errorf("packParams: p=%d does not fit in a byte (0-255)", p.P)
+	}
+
+	b := make([]byte, rsParamsRequired)
+	b[0] = byte(logN)
+	b[1] = byte(p.R)
+	b[2] = byte(p.P)
+	binary.BigEndian.PutUint16(b[3:5], uint16(p.KeyLen))
+	return b, nil
+}
+
+func unpackParams(b []byte) Params {
+	return Params{
+		Algo:   "scrypt",
+		N:      1 << b[0],
+		R:      int(b[1]),
+		P:      int(b[2]),
+		KeyLen: int(binary.BigEndian.Uint16(b[3:5])),
+	}
+}
+
+// EncodeRSFile packs params/salt/hash into the Reed-Solomon-protected
+// binary envelope described above. It only supports scrypt with a
+// 16-byte salt and a 32-byte hash, matching this tool's defaults.
+func EncodeRSFile(params Params, salt, hash []byte) ([]byte, error) {
+	if params.Algo != "" && params.Algo != "scrypt" {
+		return nil, fmt.Errorf("encodeRSFile: only scrypt is supported, got %q", params.Algo)
+	}
+	if len(salt) != rsSaltRequired {
+		return nil, fmt.Errorf("encodeRSFile: salt must be %d bytes, got %d", rsSaltRequired, len(salt))
+	}
+	if len(hash) != rsHashRequired {
+		return nil, fmt.Errorf("encodeRSFile: hash must be %d bytes, got %d", rsHashRequired, len(hash))
+	}
+
+	packed, err := packParams(params)
+	if err != nil {
+		return nil, err
+	}
+	paramShares, err := rsEncode(packed, rsParamsRequired, rsParamsTotal)
+	if err != nil {
+		return nil, err
+	}
+	saltShares, err := rsEncode(salt, rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		return nil, err
+	}
+	hashShares, err := rsEncode(hash, rsHashRequired, rsHashTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, rsFileSize)
+	out = append(out, RSMagic...)
+	out = append(out, rsVersion)
+	out = append(out, paramShares...)
+	out = append(out, saltShares...)
+	out = append(out, hashShares...)
+	return out, nil
+}
+
+// DecodeRSFile reverses EncodeRSFile, correcting bit-rot in each section
+// independently and reporting the total number of corrected shares.
+func DecodeRSFile(data []byte) (*Decoded, int, error) {
+	if len(data) != rsFileSize {
+		return nil, 0, fmt.Errorf("decodeRSFile: expected %d bytes, got %d", rsFileSize, len(data))
+	}
+	if string(data[:len(RSMagic)]) != RSMagic {
+		return nil, 0, fmt.Errorf("decodeRSFile: bad magic")
+	}
+	version := data[len(RSMagic)]
+	if version != rsVersion {
+		return nil, 0, fmt.Errorf("decodeRSFile: unsupported version %d", version)
+	}
+
+	offset := len(RSMagic) + 1
+	paramBytes, paramWarnings, err := rsDecode(data[offset:offset+rsParamsTotal], rsParamsRequired, rsParamsTotal)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decodeRSFile: params: %v", err)
+	}
+	offset += rsParamsTotal
+
+	salt, saltWarnings, err := rsDecode(data[offset:offset+rsSaltTotal], rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decodeRSFile: salt: %v", err)
+	}
+	offset += rsSaltTotal
+
+	hash, hashWarnings, err := rsDecode(data[offset:offset+rsHashTotal], rsHashRequired, rsHashTotal)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decodeRSFile: hash: %v", err)
+	}
+
+	decoded := &Decoded{
+		Format: "rs",
+		Params: unpackParams(paramBytes),
+		Salt:   salt,
+		Hash:   hash,
+	}
+	return decoded, paramWarnings + saltWarnings + hashWarnings, nil
+}
@@ -0,0 +1,152 @@
+package lab2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRSEncodeDecodeRoundTrip(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	encoded, err := rsEncode(data, rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		t.Fatalf("rsEncode: %v", err)
+	}
+	if len(encoded) != rsSaltTotal {
+		t.Fatalf("len(encoded) = %d, want %d", len(encoded), rsSaltTotal)
+	}
+
+	decoded, corrected, err := rsDecode(encoded, rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		t.Fatalf("rsDecode: %v", err)
+	}
+	if corrected != 0 {
+		t.Errorf("corrected = %d, want 0 on an untouched share set", corrected)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("decoded = %x, want %x", decoded, data)
+	}
+}
+
+func TestRSDecodeCorrectsBitRot(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	encoded, err := rsEncode(data, rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		t.Fatalf("rsEncode: %v", err)
+	}
+
+	// Flip a handful of share bytes; rsSaltTotal-rsSaltRequired=32 parity
+	// shares tolerate up to 16 corrupted shares.
+	corruptedIdx := []int{0, 5, 10}
+	for _, i := range corruptedIdx {
+		encoded[i] ^= 0xFF
+	}
+
+	decoded, corrected, err := rsDecode(encoded, rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		t.Fatalf("rsDecode with %d corrupted shares: %v", len(corruptedIdx), err)
+	}
+	if corrected != len(corruptedIdx) {
+		t.Errorf("corrected = %d, want %d", corrected, len(corruptedIdx))
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("decoded = %x, want %x", decoded, data)
+	}
+}
+
+func TestRSDecodeUncorrectable(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	encoded, err := rsEncode(data, rsSaltRequired, rsSaltTotal)
+	if err != nil {
+		t.Fatalf("rsEncode: %v", err)
+	}
+
+	// Corrupting more shares than the code can correct (more than half
+	// the parity) should surface as an error, not silently wrong output.
+	maxCorrectable := (rsSaltTotal - rsSaltRequired) / 2
+	for i := 0; i <= maxCorrectable; i++ {
+		encoded[i] ^= 0xFF
+	}
+
+	if _, _, err := rsDecode(encoded, rsSaltRequired, rsSaltTotal); err == nil {
+		t.Fatal("rsDecode with more shares corrupted than correctable: want error, got nil")
+	}
+}
+
+func TestEncodeDecodeRSFileRoundTrip(t *testing.T) {
+	params := Params{Algo: "scrypt", N: 16384, R: 8, P: 1, KeyLen: 32}
+	salt := bytes.Repeat([]byte{0xAB}, rsSaltRequired)
+	hash := bytes.Repeat([]byte{0xCD}, rsHashRequired)
+
+	file, err := EncodeRSFile(params, salt, hash)
+	if err != nil {
+		t.Fatalf("EncodeRSFile: %v", err)
+	}
+	if len(file) != rsFileSize {
+		t.Fatalf("len(file) = %d, want %d", len(file), rsFileSize)
+	}
+
+	decoded, corrected, err := DecodeRSFile(file)
+	if err != nil {
+		t.Fatalf("DecodeRSFile: %v", err)
+	}
+	if corrected != 0 {
+		t.Errorf("corrected = %d, want 0", corrected)
+	}
+	if decoded.Params != params {
+		t.Errorf("Params = %+v, want %+v", decoded.Params, params)
+	}
+	if !bytes.Equal(decoded.Salt, salt) || !bytes.Equal(decoded.Hash, hash) {
+		t.Errorf("Salt/Hash mismatch after round trip")
+	}
+}
+
+func TestEncodeDecodeRSFileHighRP(t *testing.T) {
+	// R and P at the byte boundary (255) must round-trip unchanged; values
+	// above it are rejected, see TestEncodeRSFileRejectsOutOfRangeParams.
+	params := Params{Algo: "scrypt", N: 1024, R: 255, P: 255, KeyLen: 32}
+	salt := bytes.Repeat([]byte{0xAB}, rsSaltRequired)
+	hash := bytes.Repeat([]byte{0xCD}, rsHashRequired)
+
+	file, err := EncodeRSFile(params, salt, hash)
+	if err != nil {
+		t.Fatalf("EncodeRSFile: %v", err)
+	}
+
+	decoded, _, err := DecodeRSFile(file)
+	if err != nil {
+		t.Fatalf("DecodeRSFile: %v", err)
+	}
+	if decoded.Params != params {
+		t.Errorf("Params = %+v, want %+v", decoded.Params, params)
+	}
+}
+
+func TestEncodeRSFileRejectsOutOfRangeParams(t *testing.T) {
+	salt := bytes.Repeat([]byte{0xAB}, rsSaltRequired)
+	hash := bytes.Repeat([]byte{0xCD}, rsHashRequired)
+
+	cases := []struct {
+		name   string
+		params Params
+	}{
+		{"r over 255", Params{Algo: "scrypt", N: 1024, R: 300, P: 1, KeyLen: 32}},
+		{"p over 255", Params{Algo: "scrypt", N: 1024, R: 8, P: 256, KeyLen: 32}},
+		{"N not power of two", Params{Algo: "scrypt", N: 1000, R: 8, P: 1, KeyLen: 32}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := EncodeRSFile(c.params, salt, hash); err == nil {
+				t.Fatalf("EncodeRSFile(%+v): want error, got nil", c.params)
+			}
+		})
+	}
+}
+
+func TestDecodeRSFileBadMagic(t *testing.T) {
+	file := make([]byte, rsFileSize)
+	copy(file, "NOPE")
+	if _, _, err := DecodeRSFile(file); err == nil {
+		t.Fatal("DecodeRSFile with bad magic: want error, got nil")
+	}
+}